@@ -0,0 +1,151 @@
+package qx2pgq
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/henvic/pgq"
+	"github.com/vapstack/qx"
+)
+
+// aggSQL gives the SQL aggregate function name for each qx.Agg, empty for
+// qx.AggNone which projects the expression as-is.
+var aggSQL = map[qx.Agg]string{
+	qx.AggCount:    "COUNT",
+	qx.AggSum:      "SUM",
+	qx.AggAvg:      "AVG",
+	qx.AggMin:      "MIN",
+	qx.AggMax:      "MAX",
+	qx.AggArrayAgg: "array_agg",
+}
+
+// applyAggregation wires q.Distinct, q.Select, q.GroupBy, and q.Having into
+// b. It is called from Select after q.Expr's WHERE clause has been applied,
+// since Having is validated against the same GroupBy that WHERE already
+// filtered rows for.
+func applyAggregation(b pgq.SelectBuilder, q *qx.QX) (pgq.SelectBuilder, error) {
+	if len(q.Distinct) > 0 {
+		if err := validateDistinctOn(q.Distinct, q.Order); err != nil {
+			return b, err
+		}
+		b = b.Options(fmt.Sprintf("DISTINCT ON (%s)", strings.Join(q.Distinct, ", ")))
+	}
+
+	if len(q.Select) > 0 {
+		columns := make([]string, 0, len(q.Select))
+		for _, proj := range q.Select {
+			columns = append(columns, projectionSQL(proj))
+		}
+		b = b.Columns(columns...)
+	}
+
+	if len(q.GroupBy) > 0 {
+		b = b.GroupBy(q.GroupBy...)
+	}
+
+	if q.Having.Op != qx.OpNOOP {
+		if err := validateHaving(q.Having, q.GroupBy); err != nil {
+			return b, err
+		}
+		e, err := buildExpr(rewriteHavingAggregates(q.Having))
+		if err != nil {
+			return b, fmt.Errorf("error building having expression: %w", err)
+		}
+		if e != nil {
+			b = b.Having(e)
+		}
+	}
+
+	return b, nil
+}
+
+// aggregateSQL wraps field in agg's SQL function, or returns field unchanged
+// for qx.AggNone.
+func aggregateSQL(agg qx.Agg, field string) string {
+	fn, ok := aggSQL[agg]
+	if !ok {
+		return field
+	}
+	return fmt.Sprintf("%s(%s)", fn, field)
+}
+
+func projectionSQL(proj qx.Projection) string {
+	col := aggregateSQL(proj.Agg, proj.Expr)
+	if proj.Alias != "" {
+		col = fmt.Sprintf("%s AS %s", col, proj.Alias)
+	}
+	return col
+}
+
+// rewriteHavingAggregates rewrites every leaf with exp.Agg set so its Field
+// is the aggregate call itself (e.g. "amount" becomes "SUM(amount)") before
+// buildExpr turns it into a comparison. Select's output aliases play no
+// part in this — Postgres evaluates HAVING before the output list exists,
+// so a HAVING predicate on an aggregate must repeat the aggregate
+// expression, not reference its alias.
+func rewriteHavingAggregates(exp qx.Expr) qx.Expr {
+	switch exp.Op {
+	case qx.OpAND, qx.OpOR:
+		operands := make([]qx.Expr, len(exp.Operands))
+		for i, operand := range exp.Operands {
+			operands[i] = rewriteHavingAggregates(operand)
+		}
+		exp.Operands = operands
+		return exp
+	}
+	if exp.Agg != qx.AggNone {
+		exp.Field = aggregateSQL(exp.Agg, exp.Field)
+		exp.Agg = qx.AggNone
+	}
+	return exp
+}
+
+// validateHaving rejects a Having expression that references a plain field
+// which is neither grouped by nor wrapped in an aggregate (exp.Agg). An
+// aggregate reference is always valid in HAVING, grouped or not; a bare
+// field is only valid once it's in GroupBy. Select's projection aliases are
+// deliberately not consulted here — see rewriteHavingAggregates.
+func validateHaving(having qx.Expr, groupBy []string) error {
+	grouped := make(map[string]bool, len(groupBy))
+	for _, field := range groupBy {
+		grouped[field] = true
+	}
+	return walkHavingFields(having, grouped)
+}
+
+func walkHavingFields(exp qx.Expr, grouped map[string]bool) error {
+	switch exp.Op {
+	case qx.OpNOOP:
+		return nil
+	case qx.OpAND, qx.OpOR:
+		for _, operand := range exp.Operands {
+			if err := walkHavingFields(operand, grouped); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if exp.Agg != qx.AggNone {
+		return nil
+	}
+	if exp.Field != "" && !grouped[exp.Field] {
+		return fmt.Errorf("having references field %q which is neither grouped nor an aggregate expression", exp.Field)
+	}
+	return nil
+}
+
+// validateDistinctOn rejects a Distinct whose columns aren't exactly the
+// leading columns of Order, in the same order. Postgres requires DISTINCT
+// ON's expressions to match the leftmost ORDER BY expressions; catching the
+// mismatch here gives a builder-time error instead of a runtime one.
+func validateDistinctOn(distinct []string, order []qx.Order) error {
+	if len(order) < len(distinct) {
+		return fmt.Errorf("DISTINCT ON (%s) has more columns than Order (%d); its columns must lead Order", strings.Join(distinct, ", "), len(order))
+	}
+	for i, field := range distinct {
+		if order[i].Field != field {
+			return fmt.Errorf("DISTINCT ON (%s) must match the leading Order columns; Order[%d] is %q, want %q", strings.Join(distinct, ", "), i, order[i].Field, field)
+		}
+	}
+	return nil
+}
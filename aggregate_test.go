@@ -0,0 +1,101 @@
+package qx2pgq
+
+import (
+	"testing"
+
+	"github.com/vapstack/qx"
+)
+
+func TestValidateHaving(t *testing.T) {
+	cases := []struct {
+		name    string
+		having  qx.Expr
+		groupBy []string
+		wantErr bool
+	}{
+		{
+			name:    "grouped field is allowed",
+			having:  qx.Expr{Op: qx.OpGT, Field: "status", Value: "x"},
+			groupBy: []string{"status"},
+		},
+		{
+			name:    "ungrouped plain field is rejected",
+			having:  qx.Expr{Op: qx.OpGT, Field: "amount", Value: 1},
+			groupBy: []string{"status"},
+			wantErr: true,
+		},
+		{
+			name:    "an aggregate expression is allowed even when ungrouped",
+			having:  qx.Expr{Op: qx.OpGT, Field: "amount", Agg: qx.AggSum, Value: 100},
+			groupBy: []string{"status"},
+		},
+		{
+			name: "an output alias is rejected, not treated as grouped",
+			// Mirrors a projection {Expr: "amount", Agg: Sum, Alias: "total"};
+			// HAVING must repeat SUM(amount), not reference "total".
+			having:  qx.Expr{Op: qx.OpGT, Field: "total", Value: 100},
+			groupBy: nil,
+			wantErr: true,
+		},
+		{
+			name: "AND recurses into operands",
+			having: qx.Expr{Op: qx.OpAND, Operands: []qx.Expr{
+				{Op: qx.OpEQ, Field: "status", Value: "x"},
+				{Op: qx.OpGT, Field: "amount", Agg: qx.AggSum, Value: 1},
+			}},
+			groupBy: []string{"status"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateHaving(tc.having, tc.groupBy)
+			if tc.wantErr && err == nil {
+				t.Error("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRewriteHavingAggregates(t *testing.T) {
+	having := qx.Expr{Op: qx.OpGT, Field: "amount", Agg: qx.AggSum, Value: 100}
+	rewritten := rewriteHavingAggregates(having)
+	if rewritten.Field != "SUM(amount)" {
+		t.Errorf("Field = %q, want %q", rewritten.Field, "SUM(amount)")
+	}
+	if rewritten.Agg != qx.AggNone {
+		t.Errorf("Agg = %v, want cleared after rewriting into Field", rewritten.Agg)
+	}
+}
+
+func TestValidateDistinctOn(t *testing.T) {
+	order := []qx.Order{{Field: "customer_id"}, {Field: "created_at", Desc: true}}
+
+	if err := validateDistinctOn([]string{"customer_id"}, order); err != nil {
+		t.Errorf("expected a leading-column Distinct to be valid, got: %v", err)
+	}
+
+	if err := validateDistinctOn([]string{"created_at"}, order); err == nil {
+		t.Error("expected an error when Distinct does not match the leading Order column")
+	}
+
+	if err := validateDistinctOn([]string{"customer_id", "created_at", "id"}, order); err == nil {
+		t.Error("expected an error when Distinct has more columns than Order")
+	}
+}
+
+func TestProjectionSQL(t *testing.T) {
+	got := projectionSQL(qx.Projection{Expr: "amount", Agg: qx.AggSum, Alias: "total"})
+	want := "SUM(amount) AS total"
+	if got != want {
+		t.Errorf("projectionSQL = %q, want %q", got, want)
+	}
+
+	got = projectionSQL(qx.Projection{Expr: "status"})
+	if got != "status" {
+		t.Errorf("projectionSQL = %q, want %q", got, "status")
+	}
+}
@@ -0,0 +1,90 @@
+package qx2pgq
+
+import (
+	"fmt"
+
+	"github.com/henvic/pgq"
+	"github.com/vapstack/qx"
+)
+
+// Authorizer supplies the additional predicate that scopes a query to what
+// its caller is allowed to see or change. Implementations are expected to
+// already be scoped to a subject, action, and object type (e.g. constructed
+// per-request by a policy engine); WithAuthzFilter and its Update/Delete
+// counterparts just AND whatever Filter returns into the query's own
+// qx.Expr.
+//
+// Return DenyAll to short-circuit the query to WHERE false, or AllowAll (or
+// the zero qx.Expr) to add no predicate at all.
+type Authorizer interface {
+	Filter() (qx.Expr, error)
+}
+
+// AllowAll is the Authorizer predicate that adds no restriction.
+var AllowAll = qx.Expr{Op: qx.OpNOOP}
+
+// DenyAll is the Authorizer predicate that restricts a query to no rows.
+var DenyAll = qx.Expr{Op: qx.OpFALSE}
+
+// WithAuthzFilter ANDs az's predicate into q.Expr and delegates to Select.
+// A nil az is treated as AllowAll.
+func WithAuthzFilter(b pgq.SelectBuilder, q *qx.QX, az Authorizer) (pgq.SelectBuilder, error) {
+	qq, err := withAuthz(q, az)
+	if err != nil {
+		return b, err
+	}
+	return Select(b, qq)
+}
+
+// WithAuthzFilterUpdate ANDs az's predicate into q.Expr and delegates to
+// Update, so the injected predicate counts toward Update's "must have
+// conditions" check: a q with no conditions of its own passes once az adds
+// one (DenyAll included, which yields WHERE FALSE), and only fails when az
+// adds nothing.
+func WithAuthzFilterUpdate(b pgq.UpdateBuilder, q *qx.QX, az Authorizer) (pgq.UpdateBuilder, error) {
+	qq, err := withAuthz(q, az)
+	if err != nil {
+		return b, err
+	}
+	return Update(b, qq)
+}
+
+// WithAuthzFilterDelete ANDs az's predicate into q.Expr and delegates to
+// Delete.
+func WithAuthzFilterDelete(b pgq.DeleteBuilder, q *qx.QX, az Authorizer) (pgq.DeleteBuilder, error) {
+	qq, err := withAuthz(q, az)
+	if err != nil {
+		return b, err
+	}
+	return Delete(b, qq)
+}
+
+// withAuthz returns a copy of q with az's predicate ANDed into its Expr. A
+// nil az returns q itself (or an empty qx.QX when q is nil).
+func withAuthz(q *qx.QX, az Authorizer) (*qx.QX, error) {
+	if az == nil {
+		if q == nil {
+			return &qx.QX{}, nil
+		}
+		return q, nil
+	}
+
+	filter, err := az.Filter()
+	if err != nil {
+		return nil, fmt.Errorf("error building authorization filter: %w", err)
+	}
+
+	qq := qx.QX{}
+	if q != nil {
+		qq = *q
+	}
+	if filter.Op == qx.OpNOOP {
+		return &qq, nil
+	}
+	if qq.Expr.Op == qx.OpNOOP {
+		qq.Expr = filter
+	} else {
+		qq.Expr = qx.Expr{Op: qx.OpAND, Operands: []qx.Expr{qq.Expr, filter}}
+	}
+	return &qq, nil
+}
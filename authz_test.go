@@ -0,0 +1,79 @@
+package qx2pgq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vapstack/qx"
+)
+
+type fakeAuthorizer struct {
+	filter qx.Expr
+	err    error
+}
+
+func (f fakeAuthorizer) Filter() (qx.Expr, error) { return f.filter, f.err }
+
+func TestWithAuthz(t *testing.T) {
+	t.Run("nil Authorizer leaves q untouched", func(t *testing.T) {
+		q := &qx.QX{Expr: qx.Expr{Op: qx.OpEQ, Field: "owner_id", Value: 1}}
+		got, err := withAuthz(q, nil)
+		if err != nil {
+			t.Fatalf("withAuthz: %v", err)
+		}
+		if got.Expr.Field != "owner_id" {
+			t.Errorf("expected q.Expr to be unchanged, got %+v", got.Expr)
+		}
+	})
+
+	t.Run("AllowAll adds nothing", func(t *testing.T) {
+		q := &qx.QX{Expr: qx.Expr{Op: qx.OpEQ, Field: "owner_id", Value: 1}}
+		got, err := withAuthz(q, fakeAuthorizer{filter: AllowAll})
+		if err != nil {
+			t.Fatalf("withAuthz: %v", err)
+		}
+		if got.Expr.Op != qx.OpEQ || got.Expr.Field != "owner_id" {
+			t.Errorf("expected q.Expr to be unchanged by AllowAll, got %+v", got.Expr)
+		}
+	})
+
+	t.Run("DenyAll short-circuits to WHERE false even with no existing filter", func(t *testing.T) {
+		got, err := withAuthz(&qx.QX{}, fakeAuthorizer{filter: DenyAll})
+		if err != nil {
+			t.Fatalf("withAuthz: %v", err)
+		}
+		if got.Expr.Op != qx.OpFALSE {
+			t.Errorf("expected Expr to be DenyAll, got %+v", got.Expr)
+		}
+	})
+
+	t.Run("a real filter is ANDed with the existing Expr", func(t *testing.T) {
+		q := &qx.QX{Expr: qx.Expr{Op: qx.OpEQ, Field: "owner_id", Value: 1}}
+		filter := qx.Expr{Op: qx.OpEQ, Field: "tenant_id", Value: "t1"}
+		got, err := withAuthz(q, fakeAuthorizer{filter: filter})
+		if err != nil {
+			t.Fatalf("withAuthz: %v", err)
+		}
+		if got.Expr.Op != qx.OpAND || len(got.Expr.Operands) != 2 {
+			t.Fatalf("expected an AND of both predicates, got %+v", got.Expr)
+		}
+	})
+
+	t.Run("a real filter with no existing Expr becomes the Expr", func(t *testing.T) {
+		filter := qx.Expr{Op: qx.OpEQ, Field: "tenant_id", Value: "t1"}
+		got, err := withAuthz(&qx.QX{}, fakeAuthorizer{filter: filter})
+		if err != nil {
+			t.Fatalf("withAuthz: %v", err)
+		}
+		if got.Expr.Op != qx.OpEQ || got.Expr.Field != "tenant_id" {
+			t.Errorf("expected Expr to just be the authz filter, got %+v", got.Expr)
+		}
+	})
+
+	t.Run("Authorizer errors propagate", func(t *testing.T) {
+		_, err := withAuthz(&qx.QX{}, fakeAuthorizer{err: errors.New("policy engine down")})
+		if err == nil {
+			t.Error("expected the Authorizer's error to propagate")
+		}
+	})
+}
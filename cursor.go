@@ -0,0 +1,203 @@
+package qx2pgq
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/henvic/pgq"
+	"github.com/vapstack/qx"
+)
+
+// SelectPage applies keyset (cursor-based) pagination to b in addition to
+// whatever Select applies for q.Expr and q.Order. It is the entry point for
+// qx.PagingForward and qx.PagingBackward; callers still paging by q.Offset
+// should keep using Select.
+//
+// All of q.Order must be qx.OrderBasic (a plain column, ASC or DESC) since
+// the tuple comparison below only makes sense against real columns. When
+// q.Cursor is non-nil it is validated against q.Order field-for-field (same
+// fields, same directions, same order) and compiled into a row-comparison
+// WHERE clause. b is given Limit(q.Limit+1) so the caller can detect "has
+// more" by fetching one extra row and trimming it with SplitPage.
+//
+// For qx.PagingBackward the ORDER BY is emitted with every direction
+// flipped, so Postgres can seek from the tail end using the same index it
+// would use going forward. SplitPage un-reverses the fetched rows back into
+// display order; EncodeCursor/DecodeCursor carry the boundary row's
+// order-field values between requests.
+//
+// buildKeysetClause assumes NULLS LAST in display order for every column
+// regardless of ASC/DESC, so each ORDER BY term is emitted with an explicit
+// NULLS LAST (flipped to NULLS FIRST along with the direction for backward
+// paging) rather than Postgres's default, which is NULLS LAST for ASC but
+// NULLS FIRST for DESC — left implicit, a DESC column's NULLs would sort
+// opposite of what the WHERE clause assumes.
+func SelectPage(b pgq.SelectBuilder, q *qx.QX) (pgq.SelectBuilder, error) {
+	if q == nil {
+		return b, fmt.Errorf("SelectPage requires a non-nil qx.QX")
+	}
+	if q.Paging == qx.PagingOffset {
+		return Select(b, q)
+	}
+	if q.Limit <= 0 {
+		return b, fmt.Errorf("cursor pagination requires Limit > 0")
+	}
+	for _, order := range q.Order {
+		if order.Type != qx.OrderBasic {
+			return b, fmt.Errorf("cursor pagination only supports OrderBasic fields, got %v for field %s", order.Type, order.Field)
+		}
+	}
+
+	forward := q.Paging == qx.PagingForward
+
+	if q.Cursor != nil {
+		if len(q.Cursor) != len(q.Order) {
+			return b, fmt.Errorf("cursor has %d fields but Order has %d; they must match exactly", len(q.Cursor), len(q.Order))
+		}
+		for i, order := range q.Order {
+			if q.Cursor[i].Field != order.Field || q.Cursor[i].Desc != order.Desc {
+				return b, fmt.Errorf("cursor field %d (%s) does not match order field %d (%s)", i, q.Cursor[i].Field, i, order.Field)
+			}
+		}
+		where, err := buildKeysetClause(q.Order, q.Cursor, forward)
+		if err != nil {
+			return b, fmt.Errorf("error building cursor clause: %w", err)
+		}
+		b = b.Where(where)
+	}
+
+	if q.Expr.Op != qx.OpNOOP {
+		e, err := buildExpr(q.Expr)
+		if err != nil {
+			return b, fmt.Errorf("error building expression: %w", err)
+		}
+		if e != nil {
+			b = b.Where(e)
+		}
+	}
+
+	for _, order := range q.Order {
+		desc := order.Desc
+		nulls := "NULLS LAST"
+		if !forward {
+			desc = !desc
+			nulls = "NULLS FIRST"
+		}
+		direction := "ASC"
+		if desc {
+			direction = "DESC"
+		}
+		b = b.OrderBy(fmt.Sprintf("%v %v %v", order.Field, direction, nulls))
+	}
+
+	return b.Limit(uint64(q.Limit + 1)), nil
+}
+
+// buildKeysetClause compiles the classic keyset-pagination predicate: an OR
+// of per-column ANDs, each equal on every preceding order field and strictly
+// "past the cursor" on the current one. It is equivalent to the tuple form
+// WHERE (a,b,c) > (?,?,?) when every column sorts the same direction, but
+// unrolling it by hand is what lets each column pick its own operator and
+// handle NULLS LAST, which a single row comparison in Postgres cannot do.
+func buildKeysetClause(order []qx.Order, cursor qx.Cursor, forward bool) (pgq.SQLizer, error) {
+	disjuncts := make([]pgq.SQLizer, 0, len(order))
+	for i := range order {
+		conjuncts := make([]pgq.SQLizer, 0, i+1)
+		for j := 0; j < i; j++ {
+			conjuncts = append(conjuncts, keysetEqTerm(order[j].Field, cursor[j].Value))
+		}
+		conjuncts = append(conjuncts, keysetStrictTerm(order[i].Field, order[i].Desc, cursor[i].Value, forward))
+		disjuncts = append(disjuncts, pgq.And(conjuncts...))
+	}
+	return pgq.Or(disjuncts...), nil
+}
+
+// keysetEqTerm builds the tie-break equality term for a field already
+// matched in the cursor, NULL-aware since plain `= ?` never matches NULL.
+func keysetEqTerm(field string, value any) pgq.SQLizer {
+	if value == nil {
+		return pgq.Expr(field + " IS NULL")
+	}
+	return pgq.Eq{field: value}
+}
+
+// keysetStrictTerm builds the strict "past the cursor" term for one column,
+// assuming NULLS LAST regardless of ASC/DESC: a NULL is always the last
+// value for that column, so it's always "after" any non-NULL value and
+// never "before" one.
+func keysetStrictTerm(field string, desc bool, value any, forward bool) pgq.SQLizer {
+	if value == nil {
+		if forward {
+			// NULL is already the last possible value for this column; there
+			// is nothing further "after" it to seek to.
+			return pgq.Expr("FALSE")
+		}
+		return pgq.Expr(field + " IS NOT NULL")
+	}
+
+	op := ">"
+	if desc == forward {
+		op = "<"
+	}
+	cmp := pgq.Expr(fmt.Sprintf("%s %s ?", field, op), value)
+	if forward {
+		// A NULL in this column sorts after any non-NULL cursor value.
+		return pgq.Or(cmp, pgq.Expr(field+" IS NULL"))
+	}
+	return cmp
+}
+
+// SplitPage trims the extra lookahead row that SelectPage's Limit(q.Limit+1)
+// fetches and reports whether a further page exists. For backward paging,
+// rows arrive in the flipped ORDER BY emitted by SelectPage and are
+// reversed back into display order before being returned.
+func SplitPage[T any](rows []T, limit int, forward bool) (page []T, hasMore bool) {
+	hasMore = len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if !forward {
+		reversed := make([]T, len(rows))
+		for i, row := range rows {
+			reversed[len(rows)-1-i] = row
+		}
+		rows = reversed
+	}
+	return rows, hasMore
+}
+
+// EncodeCursor packs the order-field values of a boundary row (typically
+// the first or last row of a page) into an opaque cursor string to hand
+// back to the client for the next request. values must contain an entry
+// for every field in order.
+func EncodeCursor(order []qx.Order, values map[string]any) (string, error) {
+	cursor := make(qx.Cursor, len(order))
+	for i, o := range order {
+		v, ok := values[o.Field]
+		if !ok {
+			return "", fmt.Errorf("missing value for cursor field %s", o.Field)
+		}
+		cursor[i] = qx.CursorValue{Field: o.Field, Desc: o.Desc, Value: v}
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("error encoding cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor is the inverse of EncodeCursor. The result is not validated
+// against any particular Order; SelectPage does that when the cursor is
+// used.
+func DecodeCursor(s string) (qx.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding cursor: %w", err)
+	}
+	var cursor qx.Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("error decoding cursor: %w", err)
+	}
+	return cursor, nil
+}
@@ -0,0 +1,159 @@
+package qx2pgq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/henvic/pgq"
+	"github.com/vapstack/qx"
+)
+
+func TestBuildKeysetClause_NullHandling(t *testing.T) {
+	order := []qx.Order{
+		{Field: "archived_at", Desc: true},
+		{Field: "id"},
+	}
+
+	t.Run("forward past a non-null value treats a null as after it", func(t *testing.T) {
+		cursor := qx.Cursor{
+			{Field: "archived_at", Desc: true, Value: "2026-01-01"},
+			{Field: "id", Value: 42},
+		}
+		clause, err := buildKeysetClause(order, cursor, true)
+		if err != nil {
+			t.Fatalf("buildKeysetClause: %v", err)
+		}
+		sql, _, err := clause.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		if !strings.Contains(sql, "archived_at IS NULL") {
+			t.Errorf("expected forward paging to treat NULL as past a non-null cursor value, got: %s", sql)
+		}
+	})
+
+	t.Run("forward past a null value can't go further", func(t *testing.T) {
+		cursor := qx.Cursor{
+			{Field: "archived_at", Desc: true, Value: nil},
+			{Field: "id", Value: 42},
+		}
+		clause, err := buildKeysetClause(order, cursor, true)
+		if err != nil {
+			t.Fatalf("buildKeysetClause: %v", err)
+		}
+		sql, _, err := clause.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		if !strings.Contains(sql, "FALSE") {
+			t.Errorf("expected a NULL cursor value (already last under NULLS LAST) to be unsatisfiable going forward, got: %s", sql)
+		}
+	})
+
+	t.Run("backward past a null value accepts any non-null row", func(t *testing.T) {
+		cursor := qx.Cursor{
+			{Field: "archived_at", Desc: true, Value: nil},
+			{Field: "id", Value: 42},
+		}
+		clause, err := buildKeysetClause(order, cursor, false)
+		if err != nil {
+			t.Fatalf("buildKeysetClause: %v", err)
+		}
+		sql, _, err := clause.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		if !strings.Contains(sql, "archived_at IS NOT NULL") {
+			t.Errorf("expected backward paging from a NULL cursor value to accept non-null rows, got: %s", sql)
+		}
+	})
+}
+
+func TestSelectPage_NullsOrdering(t *testing.T) {
+	q := &qx.QX{
+		Limit: 10,
+		Order: []qx.Order{
+			{Field: "archived_at", Desc: true},
+			{Field: "id"},
+		},
+	}
+
+	t.Run("forward emits NULLS LAST on every column", func(t *testing.T) {
+		q.Paging = qx.PagingForward
+		b, err := SelectPage(pgq.Select("*").From("items"), q)
+		if err != nil {
+			t.Fatalf("SelectPage: %v", err)
+		}
+		sql, _, err := b.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		if strings.Count(sql, "NULLS LAST") != 2 {
+			t.Errorf("expected NULLS LAST on both order columns, got: %s", sql)
+		}
+		if strings.Contains(sql, "NULLS FIRST") {
+			t.Errorf("did not expect NULLS FIRST for forward paging, got: %s", sql)
+		}
+	})
+
+	t.Run("backward flips direction and NULLS placement together", func(t *testing.T) {
+		q.Paging = qx.PagingBackward
+		b, err := SelectPage(pgq.Select("*").From("items"), q)
+		if err != nil {
+			t.Fatalf("SelectPage: %v", err)
+		}
+		sql, _, err := b.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		if strings.Count(sql, "NULLS FIRST") != 2 {
+			t.Errorf("expected NULLS FIRST on both order columns once flipped, got: %s", sql)
+		}
+		if !strings.Contains(sql, "archived_at ASC") {
+			t.Errorf("expected the DESC column to flip to ASC, got: %s", sql)
+		}
+		if !strings.Contains(sql, "id DESC") {
+			t.Errorf("expected the ASC column to flip to DESC, got: %s", sql)
+		}
+	})
+}
+
+func TestEncodeDecodeCursor_RoundTrip(t *testing.T) {
+	order := []qx.Order{
+		{Field: "archived_at", Desc: true},
+		{Field: "id"},
+	}
+	values := map[string]any{"archived_at": "2026-01-01", "id": float64(42)}
+
+	encoded, err := EncodeCursor(order, values)
+	if err != nil {
+		t.Fatalf("EncodeCursor: %v", err)
+	}
+
+	decoded, err := DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if len(decoded) != len(order) {
+		t.Fatalf("expected %d cursor fields, got %d", len(order), len(decoded))
+	}
+	for i, o := range order {
+		if decoded[i].Field != o.Field || decoded[i].Desc != o.Desc {
+			t.Errorf("cursor field %d = %+v, want field %s desc %v", i, decoded[i], o.Field, o.Desc)
+		}
+		if decoded[i].Value != values[o.Field] {
+			t.Errorf("cursor value %d = %v, want %v", i, decoded[i].Value, values[o.Field])
+		}
+	}
+}
+
+func TestSelectPage_RejectsNonBasicOrder(t *testing.T) {
+	q := &qx.QX{
+		Limit:  10,
+		Paging: qx.PagingForward,
+		Order:  []qx.Order{{Field: "tags", Type: qx.OrderByArrayCount}},
+	}
+	if _, err := SelectPage(pgq.Select("*").From("items"), q); err == nil {
+		t.Error("expected an error for a non-OrderBasic field under cursor pagination")
+	}
+}
@@ -0,0 +1,74 @@
+package qx2pgq
+
+import (
+	"fmt"
+
+	"github.com/henvic/pgq"
+	"github.com/vapstack/qx"
+)
+
+// ftsQueryFn maps each full-text-search op to the Postgres function that
+// turns the raw search term into a tsquery.
+var ftsQueryFn = map[qx.Op]string{
+	qx.OpFTS:          "to_tsquery",
+	qx.OpFTSPhrase:    "phraseto_tsquery",
+	qx.OpFTSWebsearch: "websearch_to_tsquery",
+}
+
+// buildFTS emits `to_tsvector(config, field) @@ <queryfn>(config, ?)`. The
+// text-search config defaults to "simple" when exp.Config is empty, and is
+// bound as a parameter rather than interpolated so a caller-supplied config
+// can never widen into arbitrary SQL.
+func buildFTS(exp qx.Expr) (pgq.SQLizer, error) {
+	term, ok := exp.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("value for %v must be a string (field: %s)", exp.Op, exp.Field)
+	}
+	queryFn, ok := ftsQueryFn[exp.Op]
+	if !ok {
+		return nil, fmt.Errorf("unsupported full-text search op: %v", exp.Op)
+	}
+
+	config := exp.Config
+	if config == "" {
+		config = "simple"
+	}
+
+	clause := fmt.Sprintf("to_tsvector(?, %s) @@ %s(?, ?)", exp.Field, queryFn)
+	cmp := pgq.Expr(clause, config, config, term)
+	if exp.Not {
+		sql, args, err := cmp.SQL()
+		if err != nil {
+			return nil, err
+		}
+		return pgq.Expr("NOT ("+sql+")", args...), nil
+	}
+	return cmp, nil
+}
+
+// FTSRankData is the order.Data payload expected for qx.OrderByFTSRank: the
+// same search term (and optional config) used in the matching qx.OpFTS
+// expression, so the ranking reflects the same query that filtered the rows.
+type FTSRankData struct {
+	Query  string
+	Config string
+}
+
+// ftsRankOrderByClause builds the ts_rank ORDER BY clause and its bind args
+// for qx.OrderByFTSRank, called from Select's order loop.
+func ftsRankOrderByClause(field string, desc bool, data any) (string, []any, error) {
+	rank, ok := data.(FTSRankData)
+	if !ok {
+		return "", nil, fmt.Errorf("order.Data for OrderByFTSRank must be qx2pgq.FTSRankData, got %T", data)
+	}
+	config := rank.Config
+	if config == "" {
+		config = "simple"
+	}
+
+	clause := fmt.Sprintf("ts_rank(to_tsvector(?, %s), to_tsquery(?, ?))", field)
+	if desc {
+		clause += " DESC"
+	}
+	return clause, []any{config, config, rank.Query}, nil
+}
@@ -0,0 +1,98 @@
+package qx2pgq
+
+import (
+	"testing"
+
+	"github.com/vapstack/qx"
+)
+
+func TestBuildFTS(t *testing.T) {
+	t.Run("defaults to the simple config", func(t *testing.T) {
+		res, err := buildExpr(qx.Expr{Op: qx.OpFTS, Field: "body", Value: "hello world"})
+		if err != nil {
+			t.Fatalf("buildExpr: %v", err)
+		}
+		sql, args, err := res.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		wantSQL := "to_tsvector(?, body) @@ to_tsquery(?, ?)"
+		if sql != wantSQL {
+			t.Errorf("sql = %q, want %q", sql, wantSQL)
+		}
+		if len(args) != 3 || args[0] != "simple" || args[1] != "simple" || args[2] != "hello world" {
+			t.Errorf("args = %v, want [simple simple hello world]", args)
+		}
+	})
+
+	t.Run("honors an explicit config", func(t *testing.T) {
+		res, err := buildExpr(qx.Expr{Op: qx.OpFTSPhrase, Field: "body", Value: "hello world", Config: "english"})
+		if err != nil {
+			t.Fatalf("buildExpr: %v", err)
+		}
+		sql, args, err := res.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		wantSQL := "to_tsvector(?, body) @@ phraseto_tsquery(?, ?)"
+		if sql != wantSQL {
+			t.Errorf("sql = %q, want %q", sql, wantSQL)
+		}
+		if args[0] != "english" || args[1] != "english" {
+			t.Errorf("args = %v, want config english in both slots", args)
+		}
+	})
+
+	t.Run("websearch variant", func(t *testing.T) {
+		res, err := buildExpr(qx.Expr{Op: qx.OpFTSWebsearch, Field: "body", Value: `"a phrase" -excluded`})
+		if err != nil {
+			t.Fatalf("buildExpr: %v", err)
+		}
+		sql, _, err := res.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		wantSQL := "to_tsvector(?, body) @@ websearch_to_tsquery(?, ?)"
+		if sql != wantSQL {
+			t.Errorf("sql = %q, want %q", sql, wantSQL)
+		}
+	})
+
+	t.Run("Not wraps the predicate", func(t *testing.T) {
+		res, err := buildExpr(qx.Expr{Op: qx.OpFTS, Field: "body", Value: "hello", Not: true})
+		if err != nil {
+			t.Fatalf("buildExpr: %v", err)
+		}
+		sql, _, err := res.SQL()
+		if err != nil {
+			t.Fatalf("SQL: %v", err)
+		}
+		if sql[:4] != "NOT " {
+			t.Errorf("sql = %q, want it wrapped in NOT (...)", sql)
+		}
+	})
+
+	t.Run("rejects a non-string value", func(t *testing.T) {
+		if _, err := buildExpr(qx.Expr{Op: qx.OpFTS, Field: "body", Value: 5}); err == nil {
+			t.Error("expected an error for a non-string FTS value")
+		}
+	})
+}
+
+func TestFtsRankOrderByClause(t *testing.T) {
+	clause, args, err := ftsRankOrderByClause("body", true, FTSRankData{Query: "hello", Config: "english"})
+	if err != nil {
+		t.Fatalf("ftsRankOrderByClause: %v", err)
+	}
+	wantClause := "ts_rank(to_tsvector(?, body), to_tsquery(?, ?)) DESC"
+	if clause != wantClause {
+		t.Errorf("clause = %q, want %q", clause, wantClause)
+	}
+	if len(args) != 3 || args[0] != "english" || args[1] != "english" || args[2] != "hello" {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, _, err := ftsRankOrderByClause("body", false, "not the right type"); err == nil {
+		t.Error("expected an error for a non-FTSRankData order.Data")
+	}
+}
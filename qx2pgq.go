@@ -31,6 +31,11 @@ func Select(b pgq.SelectBuilder, q *qx.QX) (pgq.SelectBuilder, error) {
 		}
 	}
 
+	b, err := applyAggregation(b, q)
+	if err != nil {
+		return b, err
+	}
+
 	for _, order := range q.Order {
 		if order.Field == "" {
 			continue
@@ -60,6 +65,14 @@ func Select(b pgq.SelectBuilder, q *qx.QX) (pgq.SelectBuilder, error) {
 				clause += " DESC"
 			}
 			b = b.OrderBy(clause)
+
+		case qx.OrderByFTSRank:
+
+			clause, args, err := ftsRankOrderByClause(order.Field, order.Desc, order.Data)
+			if err != nil {
+				return b, fmt.Errorf("error building order for field %s: %w", order.Field, err)
+			}
+			b = b.OrderByClause(clause, args...)
 		}
 	}
 	return b, nil
@@ -114,9 +127,23 @@ func buildExpr(exp qx.Expr) (pgq.SQLizer, error) {
 	case qx.OpHAS, qx.OpHASANY:
 		return buildArrayOp(exp)
 
-	case qx.OpPREFIX, qx.OpSUFFIX, qx.OpCONTAINS:
+	case qx.OpPREFIX, qx.OpSUFFIX, qx.OpCONTAINS,
+		qx.OpIPREFIX, qx.OpISUFFIX, qx.OpICONTAINS,
+		qx.OpSIMILAR, qx.OpREGEX, qx.OpIREGEX:
 		return buildStringOp(exp)
 
+	case qx.OpFTS, qx.OpFTSPhrase, qx.OpFTSWebsearch:
+		return buildFTS(exp)
+
+	case qx.OpFALSE:
+		return pgq.Expr("FALSE"), nil
+
+	case qx.OpISNULL:
+		return buildIsNull(exp), nil
+
+	case qx.OpISDISTINCT:
+		return buildIsDistinct(exp), nil
+
 	default:
 		return nil, fmt.Errorf("unknown op: %v", exp.Op)
 	}
@@ -161,6 +188,12 @@ func buildLogical(exp qx.Expr) (pgq.SQLizer, error) {
 }
 
 func buildComparison(exp qx.Expr) (pgq.SQLizer, error) {
+	if exp.Value == nil {
+		if exp.Op == qx.OpEQ {
+			return nil, fmt.Errorf("field %s: nil value on OpEQ is no longer accepted, use qx.OpISNULL instead", exp.Field)
+		}
+		return nil, fmt.Errorf("field %s: nil value is not valid for op %v", exp.Field, exp.Op)
+	}
 	if !isComparable(exp.Value) {
 		return nil, fmt.Errorf("value for field %s is not comparable (kind: %v)", exp.Field, reflect.TypeOf(exp.Value).Kind())
 	}
@@ -228,28 +261,51 @@ func buildArrayOp(exp qx.Expr) (pgq.SQLizer, error) {
 	return pgq.Expr(exp.Field+" "+op+" ?", exp.Value), nil
 }
 
+// stringOpSQL gives the comparison keyword for each operator buildStringOp
+// handles, split by whether it's negated.
+var stringOpSQL = map[qx.Op][2]string{
+	qx.OpPREFIX:   {"LIKE", "NOT LIKE"},
+	qx.OpSUFFIX:   {"LIKE", "NOT LIKE"},
+	qx.OpCONTAINS: {"LIKE", "NOT LIKE"},
+
+	qx.OpIPREFIX:   {"ILIKE", "NOT ILIKE"},
+	qx.OpISUFFIX:   {"ILIKE", "NOT ILIKE"},
+	qx.OpICONTAINS: {"ILIKE", "NOT ILIKE"},
+
+	qx.OpSIMILAR: {"SIMILAR TO", "NOT SIMILAR TO"},
+	qx.OpREGEX:   {"~", "!~"},
+	qx.OpIREGEX:  {"~*", "!~*"},
+}
+
 func buildStringOp(exp qx.Expr) (pgq.SQLizer, error) {
 	strVal, ok := exp.Value.(string)
 	if !ok {
 		return nil, fmt.Errorf("value for %s must be a string (field: %s)", exp.Op, exp.Field)
 	}
 
-	escapedVal := likeEscapeReplacer.Replace(strVal)
-
-	var pattern string
-	switch exp.Op {
-	case qx.OpPREFIX:
-		pattern = escapedVal + "%"
-	case qx.OpSUFFIX:
-		pattern = "%" + escapedVal
-	case qx.OpCONTAINS:
-		pattern = "%" + escapedVal + "%"
+	sql, ok := stringOpSQL[exp.Op]
+	if !ok {
+		return nil, fmt.Errorf("unsupported string op: %v", exp.Op)
 	}
-
+	keyword := sql[0]
 	if exp.Not {
-		return pgq.Expr(exp.Field+" NOT LIKE ?", exp.Field, pattern), nil
+		keyword = sql[1]
 	}
-	return pgq.Expr(exp.Field+" LIKE ?", pattern), nil
+
+	pattern := strVal
+	switch exp.Op {
+	case qx.OpPREFIX, qx.OpIPREFIX:
+		pattern = likeEscapeReplacer.Replace(strVal) + "%"
+	case qx.OpSUFFIX, qx.OpISUFFIX:
+		pattern = "%" + likeEscapeReplacer.Replace(strVal)
+	case qx.OpCONTAINS, qx.OpICONTAINS:
+		pattern = "%" + likeEscapeReplacer.Replace(strVal) + "%"
+	case qx.OpSIMILAR, qx.OpREGEX, qx.OpIREGEX:
+		// SIMILAR TO and the regex operators take the value as a pattern in
+		// their own right, so the LIKE %/_ escaping above does not apply.
+	}
+
+	return pgq.Expr(exp.Field+" "+keyword+" ?", pattern), nil
 }
 
 func hasConditions(expr qx.Expr) bool {
@@ -264,10 +320,29 @@ func hasConditions(expr qx.Expr) bool {
 	return false
 }
 
-func isComparable(v any) bool {
-	if v == nil {
-		return true // converted to IS NULL by pgq
+// buildIsNull handles qx.OpISNULL, which buildComparison no longer does
+// implicitly for a nil OpEQ value.
+func buildIsNull(exp qx.Expr) pgq.SQLizer {
+	if exp.Not {
+		return pgq.Expr(exp.Field + " IS NOT NULL")
+	}
+	return pgq.Expr(exp.Field + " IS NULL")
+}
+
+// buildIsDistinct handles qx.OpISDISTINCT. Unlike buildComparison's
+// operators, a nil Value is legitimate here: "IS DISTINCT FROM NULL" is
+// exactly how callers express "is not null" with NULL-safe semantics.
+func buildIsDistinct(exp qx.Expr) pgq.SQLizer {
+	keyword := "IS DISTINCT FROM"
+	if exp.Not {
+		keyword = "IS NOT DISTINCT FROM"
 	}
+	return pgq.Expr(exp.Field+" "+keyword+" ?", exp.Value)
+}
+
+// isComparable reports whether v (always non-nil; nil is rejected earlier
+// by buildComparison) is safe to use as a comparison value.
+func isComparable(v any) bool {
 	rt := reflect.ValueOf(v)
 	if rt.Kind() == reflect.Pointer {
 		rt = rt.Elem()
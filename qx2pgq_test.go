@@ -0,0 +1,127 @@
+package qx2pgq
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vapstack/qx"
+)
+
+func TestBuildStringOp(t *testing.T) {
+	cases := []struct {
+		name       string
+		exp        qx.Expr
+		wantSQL    string
+		wantArg    string
+		escapesPct bool
+	}{
+		{
+			name:       "ILIKE prefix escapes LIKE metacharacters",
+			exp:        qx.Expr{Op: qx.OpIPREFIX, Field: "name", Value: "50%_off"},
+			wantSQL:    "name ILIKE ?",
+			wantArg:    `50\%\_off%`,
+			escapesPct: true,
+		},
+		{
+			name:    "NOT ILIKE contains",
+			exp:     qx.Expr{Op: qx.OpICONTAINS, Field: "name", Value: "abc", Not: true},
+			wantSQL: "name NOT ILIKE ?",
+			wantArg: "%abc%",
+		},
+		{
+			name:    "SIMILAR TO does not escape the pattern",
+			exp:     qx.Expr{Op: qx.OpSIMILAR, Field: "code", Value: "A%(B|C)"},
+			wantSQL: "code SIMILAR TO ?",
+			wantArg: "A%(B|C)",
+		},
+		{
+			name:    "REGEX does not escape the pattern",
+			exp:     qx.Expr{Op: qx.OpREGEX, Field: "code", Value: "^[A-Z]+_\\d+$"},
+			wantSQL: "code ~ ?",
+			wantArg: "^[A-Z]+_\\d+$",
+		},
+		{
+			name:    "NOT IREGEX",
+			exp:     qx.Expr{Op: qx.OpIREGEX, Field: "code", Value: "^a", Not: true},
+			wantSQL: "code !~* ?",
+			wantArg: "^a",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := buildExpr(tc.exp)
+			if err != nil {
+				t.Fatalf("buildExpr: %v", err)
+			}
+			sql, args, err := res.SQL()
+			if err != nil {
+				t.Fatalf("SQL: %v", err)
+			}
+			if sql != tc.wantSQL {
+				t.Errorf("sql = %q, want %q", sql, tc.wantSQL)
+			}
+			if len(args) != 1 || args[0] != tc.wantArg {
+				t.Errorf("args = %v, want [%q]", args, tc.wantArg)
+			}
+		})
+	}
+}
+
+func TestBuildComparison_NilValueRejected(t *testing.T) {
+	_, err := buildExpr(qx.Expr{Op: qx.OpEQ, Field: "deleted_at", Value: nil})
+	if err == nil {
+		t.Fatal("expected an error for OpEQ with a nil value")
+	}
+	if !strings.Contains(err.Error(), "OpISNULL") {
+		t.Errorf("expected the error to point callers at OpISNULL, got: %v", err)
+	}
+
+	_, err = buildExpr(qx.Expr{Op: qx.OpGT, Field: "created_at", Value: nil})
+	if err == nil {
+		t.Fatal("expected an error for OpGT with a nil value")
+	}
+}
+
+func TestBuildIsNull(t *testing.T) {
+	res, err := buildExpr(qx.Expr{Op: qx.OpISNULL, Field: "deleted_at"})
+	if err != nil {
+		t.Fatalf("buildExpr: %v", err)
+	}
+	sql, _, err := res.SQL()
+	if err != nil {
+		t.Fatalf("SQL: %v", err)
+	}
+	if sql != "deleted_at IS NULL" {
+		t.Errorf("sql = %q, want %q", sql, "deleted_at IS NULL")
+	}
+
+	res, err = buildExpr(qx.Expr{Op: qx.OpISNULL, Field: "deleted_at", Not: true})
+	if err != nil {
+		t.Fatalf("buildExpr: %v", err)
+	}
+	sql, _, err = res.SQL()
+	if err != nil {
+		t.Fatalf("SQL: %v", err)
+	}
+	if sql != "deleted_at IS NOT NULL" {
+		t.Errorf("sql = %q, want %q", sql, "deleted_at IS NOT NULL")
+	}
+}
+
+func TestBuildIsDistinct_AllowsNilValue(t *testing.T) {
+	res, err := buildExpr(qx.Expr{Op: qx.OpISDISTINCT, Field: "status", Value: nil})
+	if err != nil {
+		t.Fatalf("buildExpr: %v", err)
+	}
+	sql, args, err := res.SQL()
+	if err != nil {
+		t.Fatalf("SQL: %v", err)
+	}
+	if sql != "status IS DISTINCT FROM ?" {
+		t.Errorf("sql = %q, want %q", sql, "status IS DISTINCT FROM ?")
+	}
+	if len(args) != 1 || args[0] != nil {
+		t.Errorf("args = %v, want [nil]", args)
+	}
+}